@@ -1,41 +1,218 @@
 package echozap
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"fmt"
-	"io/ioutil"
+	"io"
+	"net"
+	"net/http"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/gommon/bytes"
+	gommonbytes "github.com/labstack/gommon/bytes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 type (
-	Config struct {
+	// commonConfig holds the options shared by ZapLoggerWithConfig and SlogLoggerWithConfig. Each
+	// embeds it alongside their own backend-specific LogLevel.
+	commonConfig struct {
 		// Skipper defines a function to skip middleware.
 		Skipper Skipper
 
 		// ContextKeys defines the keys which should be added to the logger, as fields, from the context.
 		ContextKeys []interface{}
 
-		// PrintBody defines if the body of the request should be printed, if it exists.
-		PrintBody bool
+		// BodyCapture configures capturing of request/response bodies into the access log. A nil
+		// value disables capture entirely.
+		BodyCapture *BodyCapture
+
+		// TraceProvider extracts the trace and span IDs to correlate with the request. Defaults to
+		// DefaultTraceProvider, which reads the active OpenTelemetry span from the request context.
+		TraceProvider TraceProvider
+
+		// TraceIDFieldName is the field name used for the trace ID. Defaults to "trace_id".
+		TraceIDFieldName string
+
+		// SpanIDFieldName is the field name used for the span ID. Defaults to "span_id".
+		SpanIDFieldName string
+
+		// Sampler decides, after the handler has run, whether the access log entry for a request
+		// should be emitted. A nil Sampler logs every request.
+		Sampler Sampler
+
+		// MetricsRecorder, if set, observes every non-skipped request. Unlike Sampler, which only
+		// thins the access log, MetricsRecorder always runs so aggregate metrics stay accurate.
+		// See the echozap/metrics package for a Prometheus-backed implementation.
+		MetricsRecorder MetricsRecorder
+
+		// PathSkipper lists patterns matched against c.Path() (the matched Echo route template)
+		// that skip the access log, same as Skipper, without requiring a custom Skipper closure.
+		// A pattern ending in "*" matches by prefix; anything else is matched via path.Match.
+		PathSkipper []string
+
+		// StaticFields are appended verbatim to every log entry produced with this config. Typical
+		// use is tagging a RouteOverrides entry, e.g. {"component", "billing"}.
+		StaticFields []Field
+	}
+
+	Config struct {
+		commonConfig
 
 		// LogLevel selects the log level to use depending on HTTP status.
 		LogLevel func(status int) zapcore.Level
+
+		// RouteOverrides merges onto the base config for requests whose matched route template
+		// (c.Path(), e.g. "/users/:id") is a key in the map: only fields explicitly set on the
+		// override entry (non-zero Skipper, BodyCapture, LogLevel, etc.) replace the base config's,
+		// everything else is inherited. This is useful to silence health/metrics endpoints, elevate
+		// logging for sensitive routes, or disable body capture on file-upload endpoints without a
+		// custom Skipper, while still keeping the base MetricsRecorder/Sampler/TraceProvider active.
+		RouteOverrides map[string]Config
+	}
+
+	// MetricsRecorder observes a completed request's outcome. c.Path() (the matched Echo route
+	// template) should be preferred over the raw request URI when labeling metrics, to avoid
+	// cardinality explosions from path parameters.
+	MetricsRecorder interface {
+		Observe(c echo.Context, status int, latency time.Duration, reqSize, resSize int64)
+	}
+
+	// BodyCapture configures how request and response bodies are captured into the access log.
+	BodyCapture struct {
+		// MaxRequestBytes is the maximum number of request body bytes captured. Zero disables
+		// request body capture.
+		MaxRequestBytes int64
+
+		// MaxResponseBytes is the maximum number of response body bytes captured. Zero disables
+		// response body capture.
+		MaxResponseBytes int64
+
+		// ContentTypes lists the allowed content-type prefixes eligible for capture, e.g.
+		// "application/json" or "text/*". A nil slice allows any content type that isn't one of
+		// the always-excluded streaming types (SSE, websockets, multipart).
+		ContentTypes []string
+
+		// Redactor scrubs a captured body before it is logged, e.g. to strip passwords or tokens.
+		// Optional.
+		Redactor func(body []byte, contentType string) []byte
 	}
 
 	Skipper func(echo.Context) bool
+
+	// TraceProvider extracts the trace and span IDs to attach to the log entry for c. An empty
+	// traceID means no trace was found, in which case no trace fields are added.
+	TraceProvider func(c echo.Context) (traceID, spanID string)
+
+	// Field is a backend-neutral key/value pair produced by buildFields. ZapLoggerWithConfig and
+	// SlogLoggerWithConfig each translate the slice into their own structured field type.
+	Field struct {
+		Key   string
+		Value interface{}
+	}
+
+	// limitedBuffer captures up to max bytes written to it and silently discards the rest.
+	limitedBuffer struct {
+		buf bytes.Buffer
+		max int64
+	}
+
+	// bodyCaptureWriter wraps an echo.Response's writer to capture the response body, deciding
+	// whether to capture based on the Content-Type set by the handler by the time of the first
+	// write.
+	bodyCaptureWriter struct {
+		http.ResponseWriter
+		capture       *BodyCapture
+		buf           *limitedBuffer
+		checked       bool
+		shouldCollect bool
+	}
 )
 
-var DefaultConfig = Config{
+var defaultCommonConfig = commonConfig{
 	Skipper:     DefaultSkipper,
 	ContextKeys: nil,
-	PrintBody:   true,
-	LogLevel:    DefaultLogLevel,
+	BodyCapture: &BodyCapture{
+		MaxRequestBytes:  1 * gommonbytes.KB,
+		MaxResponseBytes: 1 * gommonbytes.KB,
+		ContentTypes:     []string{"application/json", "text/*"},
+	},
+	TraceProvider:    DefaultTraceProvider,
+	TraceIDFieldName: "trace_id",
+	SpanIDFieldName:  "span_id",
+}
+
+var DefaultConfig = Config{
+	commonConfig: defaultCommonConfig,
+	LogLevel:     DefaultLogLevel,
+}
+
+// mergeCommonConfig returns a copy of base with any fields explicitly set on override applied on
+// top. Fields left at their zero value on override (nil func/pointer/slice, empty string) are
+// treated as "not overridden" and fall through to base, so a RouteOverrides entry that only sets
+// e.g. LogLevel or StaticFields keeps inheriting the parent's MetricsRecorder, BodyCapture, Sampler
+// and TraceProvider rather than silently losing them.
+func mergeCommonConfig(base, override commonConfig) commonConfig {
+	merged := base
+
+	if override.Skipper != nil {
+		merged.Skipper = override.Skipper
+	}
+	if override.ContextKeys != nil {
+		merged.ContextKeys = override.ContextKeys
+	}
+	if override.BodyCapture != nil {
+		merged.BodyCapture = override.BodyCapture
+	}
+	if override.TraceProvider != nil {
+		merged.TraceProvider = override.TraceProvider
+	}
+	if override.TraceIDFieldName != "" {
+		merged.TraceIDFieldName = override.TraceIDFieldName
+	}
+	if override.SpanIDFieldName != "" {
+		merged.SpanIDFieldName = override.SpanIDFieldName
+	}
+	if override.Sampler != nil {
+		merged.Sampler = override.Sampler
+	}
+	if override.MetricsRecorder != nil {
+		merged.MetricsRecorder = override.MetricsRecorder
+	}
+	if override.PathSkipper != nil {
+		merged.PathSkipper = override.PathSkipper
+	}
+	if override.StaticFields != nil {
+		merged.StaticFields = override.StaticFields
+	}
+
+	return merged
+}
+
+// streamingContentTypes are never captured, regardless of Config.BodyCapture.ContentTypes, since
+// buffering them defeats their purpose or can grow unbounded.
+var streamingContentTypes = []string{
+	"text/event-stream",
+	"multipart/",
+}
+
+// DefaultTraceProvider reads the active OpenTelemetry span from the request context, as set up by
+// otelecho or a similar instrumentation middleware. It returns empty strings when no valid span
+// is present.
+func DefaultTraceProvider(c echo.Context) (traceID, spanID string) {
+	sc := trace.SpanContextFromContext(c.Request().Context())
+	if !sc.IsValid() {
+		return "", ""
+	}
+
+	return sc.TraceID().String(), sc.SpanID().String()
 }
 
 // ZapLogger is a middleware and zap to provide an "access log" like logging for each request.
@@ -46,92 +223,210 @@ func ZapLogger(log *zap.Logger) echo.MiddlewareFunc {
 func ZapLoggerWithConfig(log *zap.Logger, config Config) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			start := time.Now()
-
-			err := next(c)
-			if err != nil {
-				c.Error(err)
+			effective := config
+			if override, ok := config.RouteOverrides[c.Path()]; ok {
+				effective.commonConfig = mergeCommonConfig(config.commonConfig, override.commonConfig)
+				if override.LogLevel != nil {
+					effective.LogLevel = override.LogLevel
+				}
 			}
 
-			if config.Skipper(c) {
-				return err
-			}
+			return newAccessLogHandler(next, effective.commonConfig, func(c echo.Context, o requestOutcome) {
+				logLevel := DefaultLogLevel(o.status)
+				if effective.LogLevel != nil {
+					logLevel = effective.LogLevel(o.status)
+				}
 
-			req := c.Request()
-			res := c.Response()
-
-			fields := []zapcore.Field{
-				zap.String("time", time.Now().Format(time.RFC3339Nano)),
-				zap.String("remote_ip", c.RealIP()),
-				zap.String("host", req.Host),
-				zap.String("method", req.Method),
-				zap.String("uri", req.RequestURI),
-				zap.String("user_agent", req.UserAgent()),
-				zap.Int("status", res.Status),
-				zap.Int64("latency", time.Since(start).Nanoseconds()),
-				zap.String("latency_human", time.Since(start).String()),
-			}
+				logger := log
+				if o.status >= 500 {
+					logger = logger.With(zap.Error(o.err))
+				}
 
-			// add context fields
-			fields = append(fields, getContextFields(req.Context(), config.ContextKeys)...)
+				ce := logger.Check(logLevel, accessMessage(o.status))
+				if ce == nil {
+					return
+				}
 
-			headerContentLengthRaw := req.Header.Get(echo.HeaderContentLength)
-			headerContentLength, parseErr := strconv.ParseInt(headerContentLengthRaw, 10, 64)
-			if parseErr != nil {
-				headerContentLength = 0
-			}
-			fields = append(fields, zap.Int64("bytes_in", headerContentLength))
-			fields = append(fields, zap.Int64("bytes_out", res.Size))
-
-			if config.PrintBody && headerContentLength > 0 && headerContentLength < 1*bytes.KB {
-				body, err := ioutil.ReadAll(req.Body)
-				if err != nil {
-					log.Warn("echozap error decoding request body", zap.Error(err))
-				} else {
-					fields = append(fields, zap.String("body", string(body)))
+				fields := buildFields(c, effective.commonConfig, o.start, o.latency, o.bytesIn, o.err, o.reqCapture, o.resWriter)
+				zfields := make([]zapcore.Field, len(fields))
+				for i, f := range fields {
+					zfields[i] = toZapField(f)
 				}
-			}
 
-			if err != nil {
-				fields = append(fields, zap.Error(err))
-				c.Error(err)
+				ce.Write(zfields...)
+			})(c)
+		}
+	}
+}
 
-				if he, ok := err.(*echo.HTTPError); ok {
-					if he.Internal != nil {
-						fields = append(fields, zap.NamedError("internal_error", he.Internal))
-					}
-				}
-			}
+// newAccessLogHandler runs next, capturing bodies, metrics and sampling decisions as configured,
+// then calls emit with the request's outcome so the caller can check its level, build the fields
+// with buildFields and write the log entry with its own logging backend.
+func newAccessLogHandler(next echo.HandlerFunc, config commonConfig, emit func(c echo.Context, o requestOutcome)) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
 
-			id := req.Header.Get(echo.HeaderXRequestID)
-			if id == "" {
-				id = res.Header().Get(echo.HeaderXRequestID)
-				fields = append(fields, zap.String("request_id", id))
-			}
+		req := c.Request()
+		res := c.Response()
 
-			n := res.Status
+		var reqCapture *limitedBuffer
+		if bc := config.BodyCapture; bc != nil && bc.MaxRequestBytes > 0 && req.Body != nil && isCapturable(req.Header.Get(echo.HeaderContentType), bc) {
+			reqCapture = &limitedBuffer{max: bc.MaxRequestBytes}
+			req.Body = io.NopCloser(io.TeeReader(req.Body, reqCapture))
+		}
 
-			var logLevel zapcore.Level
-			if config.LogLevel != nil {
-				logLevel = config.LogLevel(n)
-			} else {
-				logLevel = DefaultLogLevel(n)
+		var resWriter *bodyCaptureWriter
+		if bc := config.BodyCapture; bc != nil && bc.MaxResponseBytes > 0 {
+			resWriter = &bodyCaptureWriter{
+				ResponseWriter: res.Writer,
+				capture:        bc,
+				buf:            &limitedBuffer{max: bc.MaxResponseBytes},
 			}
+			res.Writer = resWriter
+		}
 
-			switch {
-			case n >= 500:
-				logWithLevel(log.With(zap.Error(err)), logLevel, "Server error", fields...)
-			case n >= 400:
-				logWithLevel(log, logLevel, "Client error", fields...)
-			case n >= 300:
-				logWithLevel(log, logLevel, "Redirection", fields...)
-			default:
-				logWithLevel(log, logLevel, "Success", fields...)
-			}
+		err := next(c)
+		if err != nil {
+			c.Error(err)
+		}
+
+		skipper := config.Skipper
+		if skipper == nil {
+			skipper = DefaultSkipper
+		}
+		if skipper(c) || matchesPathPattern(c.Path(), config.PathSkipper) {
+			return err
+		}
+
+		latency := time.Since(start)
+
+		headerContentLengthRaw := req.Header.Get(echo.HeaderContentLength)
+		headerContentLength, parseErr := strconv.ParseInt(headerContentLengthRaw, 10, 64)
+		if parseErr != nil {
+			headerContentLength = 0
+		}
+
+		if config.MetricsRecorder != nil {
+			config.MetricsRecorder.Observe(c, res.Status, latency, headerContentLength, res.Size)
+		}
+
+		if config.Sampler != nil && !config.Sampler.Sample(c, res.Status, latency) {
+			return err
+		}
+
+		emit(c, requestOutcome{
+			status:     res.Status,
+			err:        err,
+			start:      start,
+			latency:    latency,
+			bytesIn:    headerContentLength,
+			reqCapture: reqCapture,
+			resWriter:  resWriter,
+		})
+
+		return nil
+	}
+}
+
+// requestOutcome carries the cheap, already-computed per-request data that buildFields needs.
+// Building the actual Field slice is deferred to each backend's emit callback, after it has
+// checked whether its configured level would even log the entry.
+type requestOutcome struct {
+	status     int
+	err        error
+	start      time.Time
+	latency    time.Duration
+	bytesIn    int64
+	reqCapture *limitedBuffer
+	resWriter  *bodyCaptureWriter
+}
+
+// buildFields assembles the neutral access log fields for a completed request.
+func buildFields(c echo.Context, config commonConfig, start time.Time, latency time.Duration, bytesIn int64, err error, reqCapture *limitedBuffer, resWriter *bodyCaptureWriter) []Field {
+	req := c.Request()
+	res := c.Response()
+
+	fields := []Field{
+		{"time", time.Now().Format(time.RFC3339Nano)},
+		{"remote_ip", c.RealIP()},
+		{"host", req.Host},
+		{"method", req.Method},
+		{"uri", req.RequestURI},
+		{"user_agent", req.UserAgent()},
+		{"status", res.Status},
+		{"latency", latency.Nanoseconds()},
+		{"latency_human", latency.String()},
+	}
+
+	for _, f := range getContextFields(req.Context(), config.ContextKeys) {
+		fields = append(fields, f)
+	}
+
+	fields = append(fields, Field{"bytes_in", bytesIn}, Field{"bytes_out", res.Size})
+
+	fields = append(fields, config.StaticFields...)
+
+	if reqCapture != nil {
+		if body := redactedBody(reqCapture.buf.Bytes(), req.Header.Get(echo.HeaderContentType), config.BodyCapture.Redactor); body != nil {
+			fields = append(fields, Field{"request_body", body})
+		}
+	}
+
+	if resWriter != nil && resWriter.shouldCollect {
+		if body := redactedBody(resWriter.buf.buf.Bytes(), res.Header().Get(echo.HeaderContentType), config.BodyCapture.Redactor); body != nil {
+			fields = append(fields, Field{"response_body", body})
+		}
+	}
+
+	traceProvider := config.TraceProvider
+	if traceProvider == nil {
+		traceProvider = DefaultTraceProvider
+	}
+	if traceID, spanID := traceProvider(c); traceID != "" {
+		traceIDFieldName := config.TraceIDFieldName
+		if traceIDFieldName == "" {
+			traceIDFieldName = defaultCommonConfig.TraceIDFieldName
+		}
+		spanIDFieldName := config.SpanIDFieldName
+		if spanIDFieldName == "" {
+			spanIDFieldName = defaultCommonConfig.SpanIDFieldName
+		}
+
+		fields = append(fields, Field{traceIDFieldName, traceID}, Field{spanIDFieldName, spanID})
+	}
+
+	if err != nil {
+		fields = append(fields, Field{"error", err})
+		c.Error(err)
 
-			return nil
+		if he, ok := err.(*echo.HTTPError); ok {
+			if he.Internal != nil {
+				fields = append(fields, Field{"internal_error", he.Internal})
+			}
 		}
 	}
+
+	id := req.Header.Get(echo.HeaderXRequestID)
+	if id == "" {
+		id = res.Header().Get(echo.HeaderXRequestID)
+		fields = append(fields, Field{"request_id", id})
+	}
+
+	return fields
+}
+
+// accessMessage is the log message for a completed request, chosen from its HTTP status.
+func accessMessage(status int) string {
+	switch {
+	case status >= 500:
+		return "Server error"
+	case status >= 400:
+		return "Client error"
+	case status >= 300:
+		return "Redirection"
+	default:
+		return "Success"
+	}
 }
 
 // DefaultSkipper returns false which processes the middleware.
@@ -151,8 +446,8 @@ func DefaultLogLevel(status int) zapcore.Level {
 	}
 }
 
-func getContextFields(ctx context.Context, keys []interface{}) []zapcore.Field {
-	fields := []zapcore.Field{}
+func getContextFields(ctx context.Context, keys []interface{}) []Field {
+	fields := []Field{}
 
 	for _, key := range keys {
 		v := ctx.Value(key)
@@ -160,14 +455,132 @@ func getContextFields(ctx context.Context, keys []interface{}) []zapcore.Field {
 			continue
 		}
 
-		fields = append(fields, zap.Any(fmt.Sprintf("%v", key), v))
+		fields = append(fields, Field{fmt.Sprintf("%v", key), v})
 	}
 
 	return fields
 }
 
-func logWithLevel(logger *zap.Logger, level zapcore.Level, msg string, fields ...zapcore.Field) {
-	if ce := logger.Check(level, msg); ce != nil {
-		ce.Write(fields...)
+// toZapField translates a neutral Field into a zapcore.Field.
+func toZapField(f Field) zapcore.Field {
+	switch v := f.Value.(type) {
+	case string:
+		return zap.String(f.Key, v)
+	case int:
+		return zap.Int(f.Key, v)
+	case int64:
+		return zap.Int64(f.Key, v)
+	case []byte:
+		return zap.ByteString(f.Key, v)
+	case error:
+		return zap.NamedError(f.Key, v)
+	default:
+		return zap.Any(f.Key, v)
+	}
+}
+
+// isCapturable reports whether a body with contentType should be captured, given bc's allow list
+// and the always-excluded streaming content types.
+func isCapturable(contentType string, bc *BodyCapture) bool {
+	if hasContentTypePrefix(contentType, streamingContentTypes) {
+		return false
+	}
+
+	if len(bc.ContentTypes) == 0 {
+		return true
+	}
+
+	return hasContentTypePrefix(contentType, bc.ContentTypes)
+}
+
+func hasContentTypePrefix(contentType string, prefixes []string) bool {
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.TrimSpace(contentType)
+
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "*")
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesPathPattern reports whether path matches any of patterns. A pattern ending in "*"
+// matches by prefix; anything else is matched with path.Match's glob syntax.
+func matchesPathPattern(p string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(p, prefix) {
+				return true
+			}
+			continue
+		}
+
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// redactedBody returns body run through redactor (if any), or nil if body is empty.
+func redactedBody(body []byte, contentType string, redactor func([]byte, string) []byte) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if redactor != nil {
+		return redactor(body, contentType)
+	}
+
+	return body
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - int64(b.buf.Len()); remaining > 0 {
+		if int64(len(p)) > remaining {
+			b.buf.Write(p[:remaining])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *bodyCaptureWriter) Write(b []byte) (int, error) {
+	if !w.checked {
+		w.checked = true
+		w.shouldCollect = isCapturable(w.Header().Get(echo.HeaderContentType), w.capture)
+	}
+
+	if w.shouldCollect {
+		w.buf.Write(b)
 	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyCaptureWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *bodyCaptureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("echozap: underlying %T is not http.Hijacker", w.ResponseWriter)
+	}
+
+	return hj.Hijack()
+}
+
+func (w *bodyCaptureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
@@ -1,12 +1,17 @@
 package echozap
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
@@ -51,6 +56,396 @@ func TestFields(t *testing.T) {
 	assert.Equal(t, zapcore.InfoLevel, logEntry.Level)
 }
 
+func TestBodyCaptureRequest(t *testing.T) {
+	e := echo.New()
+	reqBody := `{"username":"alice"}`
+	req := httptest.NewRequest(http.MethodPost, "/something", strings.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var bodySeenByHandler []byte
+	h := func(c echo.Context) error {
+		bodySeenByHandler, _ = io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLogger(logger)(h)(c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, reqBody, string(bodySeenByHandler))
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.Equal(t, reqBody, logFields["request_body"])
+}
+
+func TestBodyCaptureResponse(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLogger(logger)(h)(c)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.JSONEq(t, `{"status":"ok"}`, logFields["response_body"].(string))
+}
+
+func TestBodyCaptureRedactor(t *testing.T) {
+	config := DefaultConfig
+	config.BodyCapture = &BodyCapture{
+		MaxRequestBytes: 1024,
+		ContentTypes:    []string{"application/json"},
+		Redactor: func(body []byte, contentType string) []byte {
+			return bytes.ReplaceAll(body, []byte("secret"), []byte("***"))
+		},
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/something", strings.NewReader(`{"password":"secret"}`))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.Equal(t, `{"password":"***"}`, logFields["request_body"])
+}
+
+func TestSkippedLevelDoesNotBuildFields(t *testing.T) {
+	redactorCalled := false
+
+	config := DefaultConfig
+	config.BodyCapture = &BodyCapture{
+		MaxRequestBytes: 1024,
+		ContentTypes:    []string{"application/json"},
+		Redactor: func(body []byte, contentType string) []byte {
+			redactorCalled = true
+			return body
+		},
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/something", strings.NewReader(`{"password":"secret"}`))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, "")
+	}
+
+	// Only errors are enabled, so the 200 access log entry should never reach
+	// the point of redacting the captured body.
+	obs, logs := observer.New(zap.ErrorLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+	assert.Empty(t, logs.AllUntimed())
+	assert.False(t, redactorCalled, "Redactor should not run when the entry's level is disabled")
+}
+
+func TestBodyCaptureWriterHijackReturnsErrorWhenUnsupported(t *testing.T) {
+	w := &bodyCaptureWriter{ResponseWriter: httptest.NewRecorder()}
+
+	conn, rw, err := w.Hijack()
+
+	assert.Nil(t, conn)
+	assert.Nil(t, rw)
+	assert.Error(t, err)
+}
+
+func TestBodyCaptureSkipsStreamingContentTypes(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/something", strings.NewReader("event: ping\ndata: {}\n\n"))
+	req.Header.Set(echo.HeaderContentType, "text/event-stream")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLogger(logger)(h)(c)
+
+	assert.Nil(t, err)
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.NotContains(t, logFields, "request_body")
+}
+
+func TestSamplerSkipsLogging(t *testing.T) {
+	config := DefaultConfig
+	config.Sampler = SamplerFunc(func(c echo.Context, status int, latency time.Duration) bool {
+		return false
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, logs.Len())
+}
+
+type recordedObservation struct {
+	status  int
+	latency time.Duration
+	reqSize int64
+	resSize int64
+}
+
+type stubMetricsRecorder struct {
+	observations []recordedObservation
+}
+
+func (s *stubMetricsRecorder) Observe(c echo.Context, status int, latency time.Duration, reqSize, resSize int64) {
+	s.observations = append(s.observations, recordedObservation{status, latency, reqSize, resSize})
+}
+
+func TestMetricsRecorderObservesEveryRequestRegardlessOfSampler(t *testing.T) {
+	recorder := &stubMetricsRecorder{}
+	config := DefaultConfig
+	config.MetricsRecorder = recorder
+	config.Sampler = SamplerFunc(func(c echo.Context, status int, latency time.Duration) bool {
+		return false
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, logs.Len())
+	assert.Len(t, recorder.observations, 1)
+	assert.Equal(t, http.StatusOK, recorder.observations[0].status)
+}
+
+func TestPathSkipper(t *testing.T) {
+	config := DefaultConfig
+	config.PathSkipper = []string{"/healthz*"}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/healthz")
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 0, logs.Len())
+}
+
+func TestRouteOverrides(t *testing.T) {
+	config := DefaultConfig
+	config.RouteOverrides = map[string]Config{
+		"/users/:id": {
+			commonConfig: commonConfig{
+				Skipper:      DefaultSkipper,
+				StaticFields: []Field{{"component", "billing"}},
+			},
+			LogLevel: func(status int) zapcore.Level { return zapcore.DebugLevel },
+		},
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/users/:id")
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+
+	logEntry := logs.AllUntimed()[0]
+	assert.Equal(t, zapcore.DebugLevel, logEntry.Level)
+	assert.Equal(t, "billing", logEntry.ContextMap()["component"])
+}
+
+func TestRouteOverridesPreserveUnsetFields(t *testing.T) {
+	recorder := &stubMetricsRecorder{}
+
+	config := DefaultConfig
+	config.MetricsRecorder = recorder
+	config.RouteOverrides = map[string]Config{
+		"/users/:id": {
+			LogLevel: func(status int) zapcore.Level { return zapcore.DebugLevel },
+		},
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/users/42", strings.NewReader(`{"name":"secret"}`))
+	req.Header.Set(echo.HeaderContentType, "application/json")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/users/:id")
+
+	h := func(c echo.Context) error {
+		io.ReadAll(c.Request().Body)
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+
+	// The override only sets LogLevel, so the base config's MetricsRecorder and BodyCapture
+	// must still apply to this route rather than being dropped.
+	assert.Len(t, recorder.observations, 1)
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.Equal(t, `{"name":"secret"}`, logFields["request_body"])
+}
+
+func TestTraceFields(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    [16]byte{1},
+		SpanID:     [8]byte{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	req = req.WithContext(trace.ContextWithSpanContext(req.Context(), sc))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLogger(logger)(h)(c)
+
+	assert.Nil(t, err)
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.Equal(t, sc.TraceID().String(), logFields["trace_id"])
+	assert.Equal(t, sc.SpanID().String(), logFields["span_id"])
+}
+
+func TestTraceFieldsNoSpan(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLogger(logger)(h)(c)
+
+	assert.Nil(t, err)
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.NotContains(t, logFields, "trace_id")
+	assert.NotContains(t, logFields, "span_id")
+}
+
+func TestCustomTraceProvider(t *testing.T) {
+	config := DefaultConfig
+	config.TraceProvider = func(c echo.Context) (string, string) {
+		return "custom-trace", "custom-span"
+	}
+	config.TraceIDFieldName = "traceId"
+	config.SpanIDFieldName = "spanId"
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	obs, logs := observer.New(zap.DebugLevel)
+	logger := zap.New(obs)
+
+	err := ZapLoggerWithConfig(logger, config)(h)(c)
+
+	assert.Nil(t, err)
+
+	logFields := logs.AllUntimed()[0].ContextMap()
+	assert.Equal(t, "custom-trace", logFields["traceId"])
+	assert.Equal(t, "custom-span", logFields["spanId"])
+}
+
 func TestDefaultLogLevels(t *testing.T) {
 	tests := []logLevelTest{
 		{100, zapcore.InfoLevel},
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderObserve(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	r := NewRecorder(reg)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/users/:id")
+
+	r.Observe(c, http.StatusOK, 150*time.Millisecond, 128, 256)
+
+	metricFamilies, err := reg.Gather()
+	assert.NoError(t, err)
+
+	counter := findMetric(t, metricFamilies, "http_requests_total")
+	assert.Equal(t, float64(1), counter.GetCounter().GetValue())
+	assert.Equal(t, "/users/:id", labelValue(counter, "route"))
+}
+
+func findMetric(t *testing.T, families []*dto.MetricFamily, name string) *dto.Metric {
+	t.Helper()
+
+	for _, f := range families {
+		if f.GetName() == name {
+			return f.GetMetric()[0]
+		}
+	}
+
+	t.Fatalf("metric %q not found", name)
+	return nil
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,130 @@
+package echozap
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// Sampler decides whether an access log entry should be emitted for a request, evaluated
+	// after the handler has run.
+	Sampler interface {
+		Sample(c echo.Context, status int, latency time.Duration) bool
+	}
+
+	// SamplerFunc adapts a function to a Sampler.
+	SamplerFunc func(c echo.Context, status int, latency time.Duration) bool
+
+	// TickSampler logs the first N requests per Tick for a given method+route+status key, then
+	// every Mth request thereafter, mirroring zapcore.NewSamplerWithOptions.
+	TickSampler struct {
+		// First is the number of requests logged per key before thinning kicks in.
+		First int
+
+		// Thereafter logs every Thereafter-th request past First. Values <= 0 disable all
+		// logging past First.
+		Thereafter int
+
+		// Tick is the window over which First and Thereafter are counted. Defaults to 1 second.
+		Tick time.Duration
+
+		mu     sync.Mutex
+		counts map[string]*tickCount
+	}
+
+	tickCount struct {
+		resetAt time.Time
+		count   int
+	}
+
+	// ErrorSampler always samples responses with status >= 400 and otherwise delegates to
+	// Sampler. A handler error is seen only through the status Echo's error handler sets for it:
+	// an error returned after the response was committed keeps the committed status and is not
+	// forced through. A nil Sampler samples everything.
+	ErrorSampler struct {
+		Sampler Sampler
+	}
+
+	// LatencySampler always samples requests slower than Threshold and otherwise delegates to
+	// Sampler. A nil Sampler samples everything.
+	LatencySampler struct {
+		Threshold time.Duration
+		Sampler   Sampler
+	}
+)
+
+// Sample calls f.
+func (f SamplerFunc) Sample(c echo.Context, status int, latency time.Duration) bool {
+	return f(c, status, latency)
+}
+
+// NewTickSampler returns a TickSampler that logs the first n requests per second for a given
+// method+route+status key, then every m-th request thereafter.
+func NewTickSampler(first, thereafter int) *TickSampler {
+	return &TickSampler{
+		First:      first,
+		Thereafter: thereafter,
+		Tick:       time.Second,
+	}
+}
+
+// Sample implements Sampler.
+func (s *TickSampler) Sample(c echo.Context, status int, latency time.Duration) bool {
+	tick := s.Tick
+	if tick <= 0 {
+		tick = time.Second
+	}
+
+	key := c.Request().Method + " " + c.Path() + " " + strconv.Itoa(status)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.counts == nil {
+		s.counts = make(map[string]*tickCount)
+	}
+
+	tc, ok := s.counts[key]
+	if !ok || !now.Before(tc.resetAt) {
+		tc = &tickCount{resetAt: now.Add(tick)}
+		s.counts[key] = tc
+	}
+	tc.count++
+
+	if tc.count <= s.First {
+		return true
+	}
+	if s.Thereafter <= 0 {
+		return false
+	}
+
+	return (tc.count-s.First)%s.Thereafter == 0
+}
+
+// Sample implements Sampler.
+func (s *ErrorSampler) Sample(c echo.Context, status int, latency time.Duration) bool {
+	if status >= 400 {
+		return true
+	}
+	if s.Sampler == nil {
+		return true
+	}
+
+	return s.Sampler.Sample(c, status, latency)
+}
+
+// Sample implements Sampler.
+func (s *LatencySampler) Sample(c echo.Context, status int, latency time.Duration) bool {
+	if latency > s.Threshold {
+		return true
+	}
+	if s.Sampler == nil {
+		return true
+	}
+
+	return s.Sampler.Sample(c, status, latency)
+}
@@ -0,0 +1,68 @@
+package echozap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTickSampler(t *testing.T) {
+	s := NewTickSampler(2, 3)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/something")
+
+	var got []bool
+	for i := 0; i < 8; i++ {
+		got = append(got, s.Sample(c, http.StatusOK, 0))
+	}
+
+	assert.Equal(t, []bool{true, true, false, false, true, false, false, true}, got)
+}
+
+func TestTickSamplerResetsPerKey(t *testing.T) {
+	s := NewTickSampler(1, 0)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/something")
+
+	assert.True(t, s.Sample(c, http.StatusOK, 0))
+	assert.True(t, s.Sample(c, http.StatusNotFound, 0))
+}
+
+func TestErrorSampler(t *testing.T) {
+	s := &ErrorSampler{Sampler: SamplerFunc(func(echo.Context, int, time.Duration) bool { return false })}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.True(t, s.Sample(c, http.StatusInternalServerError, 0))
+	assert.False(t, s.Sample(c, http.StatusOK, 0))
+}
+
+func TestLatencySampler(t *testing.T) {
+	s := &LatencySampler{
+		Threshold: 100 * time.Millisecond,
+		Sampler:   SamplerFunc(func(echo.Context, int, time.Duration) bool { return false }),
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	assert.True(t, s.Sample(c, http.StatusOK, 200*time.Millisecond))
+	assert.False(t, s.Sample(c, http.StatusOK, 50*time.Millisecond))
+}
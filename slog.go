@@ -0,0 +1,103 @@
+package echozap
+
+import (
+	"log/slog"
+
+	"github.com/labstack/echo/v4"
+)
+
+type (
+	// SlogConfig is the slog equivalent of Config, for projects that want echozap's access log
+	// without depending on zap.
+	SlogConfig struct {
+		commonConfig
+
+		// LogLevel selects the log level to use depending on HTTP status.
+		LogLevel func(status int) slog.Level
+
+		// RouteOverrides merges onto the base config for requests whose matched route template
+		// (c.Path(), e.g. "/users/:id") is a key in the map. See Config.RouteOverrides.
+		RouteOverrides map[string]SlogConfig
+	}
+)
+
+var DefaultSlogConfig = SlogConfig{
+	commonConfig: defaultCommonConfig,
+	LogLevel:     DefaultSlogLevel,
+}
+
+// SlogLogger is a middleware that uses slog to provide an "access log" like logging for each
+// request. It produces the same fields as ZapLogger.
+func SlogLogger(l *slog.Logger) echo.MiddlewareFunc {
+	return SlogLoggerWithConfig(l, DefaultSlogConfig)
+}
+
+func SlogLoggerWithConfig(l *slog.Logger, config SlogConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			effective := config
+			if override, ok := config.RouteOverrides[c.Path()]; ok {
+				effective.commonConfig = mergeCommonConfig(config.commonConfig, override.commonConfig)
+				if override.LogLevel != nil {
+					effective.LogLevel = override.LogLevel
+				}
+			}
+
+			return newAccessLogHandler(next, effective.commonConfig, func(c echo.Context, o requestOutcome) {
+				logLevel := DefaultSlogLevel(o.status)
+				if effective.LogLevel != nil {
+					logLevel = effective.LogLevel(o.status)
+				}
+
+				logger := l
+				if o.status >= 500 && o.err != nil {
+					logger = logger.With(slog.Any("error", o.err))
+				}
+
+				ctx := c.Request().Context()
+				if !logger.Enabled(ctx, logLevel) {
+					return
+				}
+
+				fields := buildFields(c, effective.commonConfig, o.start, o.latency, o.bytesIn, o.err, o.reqCapture, o.resWriter)
+				attrs := make([]slog.Attr, len(fields))
+				for i, f := range fields {
+					attrs[i] = toSlogAttr(f)
+				}
+
+				logger.LogAttrs(ctx, logLevel, accessMessage(o.status), attrs...)
+			})(c)
+		}
+	}
+}
+
+// DefaultSlogLevel is Error for HTTP 5xx, Warn for 4xx, and Info otherwise. It mirrors
+// DefaultLogLevel.
+func DefaultSlogLevel(status int) slog.Level {
+	switch {
+	case status >= 500:
+		return slog.LevelError
+	case status >= 400:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// toSlogAttr translates a neutral Field into a slog.Attr.
+func toSlogAttr(f Field) slog.Attr {
+	switch v := f.Value.(type) {
+	case string:
+		return slog.String(f.Key, v)
+	case int:
+		return slog.Int(f.Key, v)
+	case int64:
+		return slog.Int64(f.Key, v)
+	case []byte:
+		return slog.String(f.Key, string(v))
+	case error:
+		return slog.String(f.Key, v.Error())
+	default:
+		return slog.Any(f.Key, v)
+	}
+}
@@ -0,0 +1,115 @@
+package echozap
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlogFields(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	err := SlogLogger(logger)(h)(c)
+	assert.Nil(t, err)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+
+	assert.Equal(t, "Success", entry["msg"])
+	assert.Equal(t, float64(200), entry["status"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/something", entry["uri"])
+	assert.Equal(t, "example.com", entry["host"])
+	assert.Equal(t, float64(0), entry["bytes_in"])
+	assert.Equal(t, float64(0), entry["bytes_out"])
+}
+
+func TestSlogDefaultLogLevels(t *testing.T) {
+	tests := []struct {
+		httpStatus    int
+		expectedLevel slog.Level
+	}{
+		{200, slog.LevelInfo},
+		{301, slog.LevelInfo},
+		{400, slog.LevelWarn},
+		{500, slog.LevelError},
+	}
+
+	for _, test := range tests {
+		handler := func(c echo.Context) error {
+			return c.NoContent(test.httpStatus)
+		}
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/something", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		err := SlogLogger(logger)(handler)(c)
+		assert.Nil(t, err)
+
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, test.expectedLevel.String(), entry["level"])
+	}
+}
+
+func TestSlogServerErrorWithoutGoErrorOmitsErrorField(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.NoContent(http.StatusInternalServerError)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	err := SlogLogger(logger)(h)(c)
+	assert.Nil(t, err)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.NotContains(t, entry, "error")
+}
+
+func TestSlogLoggerRespectsLevelFilter(t *testing.T) {
+	config := DefaultSlogConfig
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/something", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	h := func(c echo.Context) error {
+		return c.String(http.StatusOK, "")
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	err := SlogLoggerWithConfig(logger, config)(h)(c)
+	assert.Nil(t, err)
+	assert.Empty(t, buf.Bytes())
+}